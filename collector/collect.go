@@ -13,18 +13,27 @@ import (
 	"time"
 
 	"github.com/gagliardetto/solana-go"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog/log"
 	"golang.org/x/sync/errgroup"
 )
 
 const (
 	defaultSampleSize = 1
+	// defaultSubscriptionWindow bounds how long a tick keeps an
+	// accountSubscribe/programSubscribe/Geyser stream open when
+	// Config.SubscriptionWindowSeconds isn't set. It's intentionally much
+	// shorter than a typical poll interval: unlike Interval, every RPC with
+	// a subscription transport enabled pays this cost on every tick.
+	defaultSubscriptionWindow = 5 * time.Second
 )
 
 type Collector struct {
-	notifier *Notifier
-	interval time.Duration
-	cfg      *Config
+	notifier           Notifier
+	interval           time.Duration
+	subscriptionWindow time.Duration
+	cfg                *Config
+	metrics            *Metrics
 }
 
 func New(
@@ -59,27 +68,34 @@ func New(
 		cfg.Accounts = append(cfg.Accounts, DefaultAccountsToRequest...)
 	}
 
-	notifier := NewNotifier()
+	subscriptionWindow := defaultSubscriptionWindow
+	if cfg.SubscriptionWindowSeconds > 0 {
+		subscriptionWindow = time.Duration(cfg.SubscriptionWindowSeconds) * time.Second
+	}
 
 	return &Collector{
-		notifier: notifier,
-		cfg:      &cfg,
-		interval: interval,
+		notifier:           buildNotifier(&cfg),
+		cfg:                &cfg,
+		metrics:            NewMetrics(prometheus.DefaultRegisterer),
+		interval:           interval,
+		subscriptionWindow: subscriptionWindow,
 	}, nil
 }
 
 func (c *Collector) Run() error {
 	ctx := context.Background()
+	go Serve(ctx, c.cfg.MetricsAddr)
+
 	ticker := time.NewTicker(c.interval)
 	defer ticker.Stop()
 
 	// to triger ticker right away
 	for ; true; <-ticker.C {
 		for _, rpc := range c.cfg.RPCs {
-			r, err := collectResults(ctx, rpc, c.cfg.Accounts)
+			r, err := collectResults(ctx, rpc, c.cfg.Accounts, c.cfg.Programs, c.cfg.ProgramAccountsProbes, c.subscriptionWindow, c.metrics)
 			if err != nil {
 				log.Err(err).Str("rpc_name", rpc.ID).Msg("collectResults")
-				c.notify(rpc.ID + " " + err.Error())
+				c.notify(ctx, Event{RPCID: rpc.ID, Err: err.Error()})
 				continue
 			}
 
@@ -95,20 +111,53 @@ func (c *Collector) Run() error {
 					}
 
 					// notify only the first error
-					c.notify(r.RPCID + " " + m.ErrMsgs[0])
+					c.notify(ctx, Event{
+						RPCID:       r.RPCID,
+						Method:      m.Name,
+						Err:         m.ErrMsgs[0],
+						SampleCount: len(m.StatsSamples),
+					})
 					continue
 				}
 
-				log.Debug().
+				event := log.Debug().
 					Str("rpc_name", r.RPCID).
 					Int("sample_size", rpc.SampleSize).
 					Str("method_name", m.Name).
-					Float64("avg_frt", m.AvgFirstResponseTime).
-					Float64("avg_trt", m.AvgTotalResponseTime).
 					Int("positive_count", m.PositiveResponseCount).
 					Int("negative_count", m.NegativeResponseCount).
 					Float64("total_data_retrieved_kb", m.TotalDataRetrieved/1048576).
-					Msg("rpc results")
+					Str("throughput", FormatThroughput(int64(m.TotalDataRetrieved), c.interval))
+
+				// Subscription pseudo-methods (accountSubscribe, programSubscribe,
+				// geyserAccountSubscribe) don't have a request/response round trip,
+				// so they're logged from MethodStats.Subscription instead of the
+				// Avg*/Latency fields the HTTP methods populate.
+				if m.Subscription != nil {
+					event = event.
+						Int64("time_to_first_notification_ms", m.Subscription.TimeToFirstNotification).
+						Int("notification_count", m.Subscription.NotificationCount).
+						Float64("notifications_per_slot", m.Subscription.NotificationsPerSlot).
+						Float64("jitter_stddev_ms", m.Subscription.JitterStdDevMs).
+						Int("dropped_count", m.Subscription.DroppedCount).
+						Int("reconnect_count", m.Subscription.ReconnectCount)
+				} else {
+					event = event.
+						Float64("avg_frt", m.AvgFirstResponseTime).
+						Float64("avg_trt", m.AvgTotalResponseTime)
+				}
+
+				if m.Latency != nil {
+					event = event.
+						Float64("p50_ms", m.Latency.P50).
+						Float64("p95_ms", m.Latency.P95).
+						Float64("p99_ms", m.Latency.P99).
+						Float64("max_ms", m.Latency.Max).
+						Float64("stddev_ms", m.Latency.StdDev).
+						Float64("percent_within_budget_ms", m.Latency.PercentWithinBudgetMs)
+				}
+
+				event.Msg("rpc results")
 			}
 			fmt.Println("---------------------------------------------------------------------------------------")
 		}
@@ -117,39 +166,78 @@ func (c *Collector) Run() error {
 	return nil
 }
 
-func (c *Collector) notify(text string) {
-	if c.notifier != nil {
-		err := c.notifier.Notify(text)
-		if err != nil {
-			log.Debug().Err(err).Msg("Run")
-		}
+func (c *Collector) notify(ctx context.Context, event Event) {
+	if err := c.notifier.Notify(ctx, event); err != nil {
+		log.Debug().Err(err).Msg("Run")
 	}
 }
 
 // collectResults collects the results for a given rpc configuration for each method.
 // n specifies the amount of calls to perform for each method.
 // The methods to be tested are getAccountInfo, getMultipleAccounts, and getProgramAccounts.
-func collectResults(ctx context.Context, cfg *RPCConfig, accounts []solana.PublicKey) (*Report, error) {
+// When cfg.WSEndpoint is set, accountSubscribe is benchmarked too, and so is
+// programSubscribe when programs is non-empty; each is held open for window
+// before its stats are collected.
+func collectResults(
+	ctx context.Context,
+	cfg *RPCConfig,
+	accounts []solana.PublicKey,
+	programs []solana.PublicKey,
+	probes []ProgramAccountsProbe,
+	window time.Duration,
+	metrics *Metrics,
+) (*Report, error) {
 	result := &Report{
 		RPCID:        cfg.ID,
 		MethodsStats: make([]*MethodStats, 0),
 	}
 
 	// getAccountInfo
-	report, err := getAccountInfo(ctx, cfg, accounts[0])
+	report, err := getAccountInfo(ctx, cfg, accounts[0], metrics)
 	if err != nil {
 		return nil, err
 	}
 	result.MethodsStats = append(result.MethodsStats, report)
 
 	// getMultipleAccounts
-	report, err = getMultipleAccounts(ctx, cfg, accounts)
+	report, err = getMultipleAccounts(ctx, cfg, accounts, metrics)
 	if err != nil {
 		return nil, err
 	}
 	result.MethodsStats = append(result.MethodsStats, report)
 
-	// getProgramAccounts
+	// getProgramAccounts, swept across the configured size/filter matrix
+	for _, probe := range probes {
+		report, err = getProgramAccounts(ctx, cfg, probe, metrics)
+		if err != nil {
+			return nil, err
+		}
+		result.MethodsStats = append(result.MethodsStats, report)
+	}
+
+	if cfg.WSEndpoint != "" {
+		report, err = collectAccountSubscribeStats(ctx, cfg, accounts[0], window, metrics)
+		if err != nil {
+			return nil, err
+		}
+		result.MethodsStats = append(result.MethodsStats, report)
+
+		if len(programs) > 0 {
+			report, err = collectProgramSubscribeStats(ctx, cfg, programs[0], window, metrics)
+			if err != nil {
+				return nil, err
+			}
+			result.MethodsStats = append(result.MethodsStats, report)
+		}
+	}
+
+	if cfg.GRPC != nil {
+		report, err = collectGeyserAccountSubscribeStats(ctx, cfg, accounts[0], window, metrics)
+		if err != nil {
+			return nil, err
+		}
+		result.MethodsStats = append(result.MethodsStats, report)
+	}
 
 	return result, nil
 }
@@ -158,6 +246,7 @@ func getAccountInfo(
 	ctx context.Context,
 	rpc *RPCConfig,
 	account solana.PublicKey,
+	metrics *Metrics,
 ) (*MethodStats, error) {
 	call := RPCCall{
 		JsonRPC: "2.0",
@@ -173,13 +262,14 @@ func getAccountInfo(
 		},
 	}
 
-	return collectStats(ctx, rpc, &call, "getAccountInfo")
+	return collectStats(ctx, rpc, &call, "getAccountInfo", metrics)
 }
 
 func getMultipleAccounts(
 	ctx context.Context,
 	rpc *RPCConfig,
 	accounts []solana.PublicKey,
+	metrics *Metrics,
 ) (*MethodStats, error) {
 	accs := make([]string, len(accounts))
 	for i := range accounts {
@@ -200,17 +290,20 @@ func getMultipleAccounts(
 		},
 	}
 
-	return collectStats(ctx, rpc, &call, "getMultipleAccounts")
+	return collectStats(ctx, rpc, &call, "getMultipleAccounts", metrics)
 }
 
 // collectStats collect stats about a given call, performing the specified amount of calls. All the calls
 // are performed sequentially no goroutine are dispatched here, and respecting
-// the rate limit of the RPC provider.
+// the rate limit of the RPC provider. Every sample is also recorded against
+// metrics so p50/p95/p99 latency can be graphed in Grafana instead of relying
+// on the in-process averages below.
 func collectStats(
 	ctx context.Context,
 	rpc *RPCConfig,
 	call *RPCCall,
 	name string,
+	metrics *Metrics,
 ) (*MethodStats, error) {
 	result := &MethodStats{
 		Name: name,
@@ -232,8 +325,9 @@ func collectStats(
 	}()
 
 	var (
-		g  errgroup.Group
-		mu sync.Mutex
+		g        errgroup.Group
+		mu       sync.Mutex
+		quantile = NewQuantileTracker(float64(rpc.BudgetMs))
 	)
 
 	counter := make(map[int64]int64)
@@ -241,7 +335,7 @@ func collectStats(
 		g.Go(func() error {
 			<-bucket
 
-			stats, err := post(ctx, rpc, call)
+			stats, err := postWithRetry(ctx, rpc, call)
 			if err != nil {
 				return err
 			}
@@ -252,8 +346,13 @@ func collectStats(
 				result.ErrMsgs = append(result.ErrMsgs, stats.Err)
 			}
 			counter[time.Now().Unix()]++
+			quantile.Observe(float64(stats.TotalResponseTime))
 			mu.Unlock()
 
+			if metrics != nil {
+				metrics.Observe(rpc.ID, name, stats)
+			}
+
 			if stats.StatusCode != http.StatusOK {
 				result.NegativeResponseCount++
 				return nil
@@ -275,6 +374,7 @@ func collectStats(
 
 	result.AvgFirstResponseTime /= float64(rpc.SampleSize)
 	result.AvgTotalResponseTime /= float64(rpc.SampleSize)
+	result.Latency = quantile.Snapshot()
 
 	return result, nil
 }
@@ -329,6 +429,12 @@ func post(
 		if stats.FirstResponseTime == 0 {
 			stats.FirstResponseTime = time.Now().Sub(start).Milliseconds()
 		}
+		if stats.TimeTo1KB == 0 && stats.TotalDataRetrieved >= 1024 {
+			stats.TimeTo1KB = time.Now().Sub(start).Milliseconds()
+		}
+		if stats.TimeTo1MB == 0 && stats.TotalDataRetrieved >= 1048576 {
+			stats.TimeTo1MB = time.Now().Sub(start).Milliseconds()
+		}
 
 		if err != nil {
 			if errors.Is(err, io.EOF) {
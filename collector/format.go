@@ -0,0 +1,28 @@
+package collector
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+// FormatThroughput renders bytes transferred over elapsed as a human
+// readable rate, e.g. "1.2 MB/s".
+func FormatThroughput(bytes int64, elapsed time.Duration) string {
+	if elapsed <= 0 {
+		return "0 B/s"
+	}
+
+	bytesPerSec := float64(bytes) / elapsed.Seconds()
+	return fmt.Sprintf("%s/s", humanize.Bytes(uint64(bytesPerSec)))
+}
+
+// FormatRate renders a sample count over elapsed as "<n> req/s".
+func FormatRate(samples int, elapsed time.Duration) string {
+	if elapsed <= 0 {
+		return "0.0 req/s"
+	}
+
+	return fmt.Sprintf("%.1f req/s", float64(samples)/elapsed.Seconds())
+}
@@ -0,0 +1,169 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+const geyserAccountSubscribeMethod = "geyserAccountSubscribe"
+
+// collectGeyserAccountSubscribeStats mirrors collectAccountSubscribeStats,
+// but over a Yellowstone/Geyser gRPC subscription instead of the JSON-RPC
+// websocket one.
+func collectGeyserAccountSubscribeStats(
+	ctx context.Context,
+	rpc *RPCConfig,
+	account solana.PublicKey,
+	window time.Duration,
+	metrics *Metrics,
+) (*MethodStats, error) {
+	result := &MethodStats{Name: geyserAccountSubscribeMethod}
+
+	stats, err := readGeyserAccountStream(ctx, rpc.GRPC, account, window)
+	if err != nil {
+		result.ErrMsgs = append(result.ErrMsgs, err.Error())
+		result.NegativeResponseCount++
+		return result, nil
+	}
+
+	result.PositiveResponseCount++
+	result.Subscription = stats
+	result.TotalDataRetrieved = float64(stats.TotalDataRetrieved)
+	stats.JitterStdDevMs = jitterStddev(stats)
+
+	if metrics != nil {
+		metrics.Observe(rpc.ID, geyserAccountSubscribeMethod, &Stats{
+			StatusCode:        200,
+			FirstResponseTime: stats.TimeToFirstNotification,
+		})
+	}
+
+	return result, nil
+}
+
+// readGeyserAccountStream drains account update notifications for window,
+// redialing whenever the stream drops so a single flaky reconnect doesn't
+// sink the whole sample.
+func readGeyserAccountStream(
+	ctx context.Context,
+	cfg *GRPCConfig,
+	account solana.PublicKey,
+	window time.Duration,
+) (*SubscriptionStats, error) {
+	if cfg == nil || cfg.Endpoint == "" {
+		return nil, fmt.Errorf("geyserAccountSubscribe: no grpc endpoint configured")
+	}
+
+	deadline, cancel := context.WithTimeout(ctx, window)
+	defer cancel()
+
+	stats := &SubscriptionStats{}
+	start := time.Now()
+	var (
+		last     time.Time
+		lastSlot uint64
+	)
+
+	for deadline.Err() == nil {
+		stream, conn, err := dialGeyserAccountStream(deadline, cfg, account)
+		if err != nil {
+			if stats.NotificationCount > 0 {
+				stats.ReconnectCount++
+				continue
+			}
+			return nil, err
+		}
+
+		for {
+			update, err := stream.Recv()
+			if err != nil {
+				conn.Close()
+				if deadline.Err() != nil {
+					break
+				}
+				stats.ReconnectCount++
+				break
+			}
+
+			acc := update.GetAccount()
+			if acc == nil {
+				continue
+			}
+
+			now := time.Now()
+			if stats.NotificationCount == 0 {
+				stats.TimeToFirstNotification = now.Sub(start).Milliseconds()
+			} else {
+				stats.InterNotificationLatencies = append(stats.InterNotificationLatencies, now.Sub(last).Milliseconds())
+				if acc.GetSlot() > lastSlot+1 {
+					stats.GapCount += int(acc.GetSlot() - lastSlot - 1)
+				}
+			}
+
+			stats.NotificationCount++
+			stats.TotalDataRetrieved += int64(len(acc.GetAccount().GetData()))
+			last = now
+			lastSlot = acc.GetSlot()
+		}
+
+		if deadline.Err() != nil {
+			break
+		}
+	}
+
+	if stats.NotificationCount == 0 {
+		return nil, fmt.Errorf("geyserAccountSubscribe: no updates received within %s", window)
+	}
+
+	stats.NotificationsPerSlot = notificationsPerSlot(stats.NotificationCount, time.Since(start))
+
+	return stats, nil
+}
+
+func dialGeyserAccountStream(
+	ctx context.Context,
+	cfg *GRPCConfig,
+	account solana.PublicKey,
+) (pb.Geyser_SubscribeClient, *grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if cfg.Token != "" {
+		creds = credentials.NewTLS(nil)
+	}
+
+	conn, err := grpc.NewClient(cfg.Endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	callCtx := ctx
+	if cfg.Token != "" {
+		callCtx = metadata.AppendToOutgoingContext(ctx, "x-token", cfg.Token)
+	}
+
+	client := pb.NewGeyserClient(conn)
+	stream, err := client.Subscribe(callCtx)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	req := &pb.SubscribeRequest{
+		Accounts: map[string]*pb.SubscribeRequestFilterAccounts{
+			"rpc-notifier": {Account: []string{account.String()}},
+		},
+	}
+	if err := stream.Send(req); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	return stream, conn, nil
+}
@@ -0,0 +1,94 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog/log"
+)
+
+const defaultMetricsAddr = ":2112"
+
+// outcome labels used across the metrics below.
+const (
+	outcomePositive = "positive"
+	outcomeNegative = "negative"
+)
+
+// Metrics holds the Prometheus collectors registered for a Collector run.
+type Metrics struct {
+	FirstResponseTime *prometheus.HistogramVec
+	TotalResponseTime *prometheus.HistogramVec
+	ResponseCount     *prometheus.CounterVec
+	BytesRetrieved    *prometheus.GaugeVec
+}
+
+// NewMetrics registers the rpc-notifier collectors against reg and returns
+// the handle used to record samples from collectStats.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	factory := promauto.With(reg)
+
+	return &Metrics{
+		FirstResponseTime: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "rpc_notifier",
+			Name:      "first_response_time_ms",
+			Help:      "Time to the first byte of the RPC response, in milliseconds.",
+			Buckets:   prometheus.ExponentialBuckets(5, 2, 12),
+		}, []string{"rpc_id", "method", "outcome"}),
+		TotalResponseTime: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "rpc_notifier",
+			Name:      "total_response_time_ms",
+			Help:      "Time to the fully read RPC response, in milliseconds.",
+			Buckets:   prometheus.ExponentialBuckets(5, 2, 12),
+		}, []string{"rpc_id", "method", "outcome"}),
+		ResponseCount: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "rpc_notifier",
+			Name:      "response_total",
+			Help:      "Number of RPC responses observed, labelled by outcome.",
+		}, []string{"rpc_id", "method", "outcome"}),
+		BytesRetrieved: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "rpc_notifier",
+			Name:      "bytes_retrieved",
+			Help:      "Size in bytes of the last RPC response body read.",
+		}, []string{"rpc_id", "method"}),
+	}
+}
+
+// Observe records a single sample against the histograms, counter and gauge
+// for the given provider/method.
+func (m *Metrics) Observe(rpcID, method string, stats *Stats) {
+	outcome := outcomePositive
+	if stats.StatusCode != http.StatusOK {
+		outcome = outcomeNegative
+	}
+
+	m.FirstResponseTime.WithLabelValues(rpcID, method, outcome).Observe(float64(stats.FirstResponseTime))
+	m.TotalResponseTime.WithLabelValues(rpcID, method, outcome).Observe(float64(stats.TotalResponseTime))
+	m.ResponseCount.WithLabelValues(rpcID, method, outcome).Inc()
+	m.BytesRetrieved.WithLabelValues(rpcID, method).Set(float64(stats.TotalDataRetrieved))
+}
+
+// Serve starts the /metrics HTTP endpoint and blocks until ctx is cancelled.
+// It is meant to be run in its own goroutine alongside Collector.Run.
+func Serve(ctx context.Context, addr string) {
+	if addr == "" {
+		addr = defaultMetricsAddr
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	srv := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	log.Debug().Str("addr", addr).Msg("serving prometheus metrics")
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Err(err).Msg("metrics server")
+	}
+}
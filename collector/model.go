@@ -16,15 +16,96 @@ var (
 )
 
 type Config struct {
-	RPCs     []*RPCConfig       `json:"rpcs"`
-	Accounts []solana.PublicKey `json:"accounts"`
+	RPCs        []*RPCConfig       `json:"rpcs"`
+	Accounts    []solana.PublicKey `json:"accounts"`
+	Programs    []solana.PublicKey `json:"programs"`
+	MetricsAddr string             `json:"metricsAddr"`
+	// Notifiers configures the alerting sinks, fanned out via a
+	// CompositeNotifier. Leave empty to alert nowhere.
+	Notifiers []NotifierConfig `json:"notifiers,omitempty"`
+	// ThrottleWindowSeconds, when set, wraps the composite notifier in a
+	// ThrottledNotifier so identical alerts within the window collapse
+	// into one message with a count.
+	ThrottleWindowSeconds int `json:"throttleWindowSeconds,omitempty"`
+	// ProgramAccountsProbes drives a size/filter matrix of getProgramAccounts
+	// calls, so a small filtered result, a medium one and an unfiltered
+	// whole-program dump can all be compared for the same provider.
+	ProgramAccountsProbes []ProgramAccountsProbe `json:"programAccountsProbes,omitempty"`
+	// SubscriptionWindowSeconds is how long accountSubscribe/programSubscribe/
+	// Geyser streams are kept open per tick. It's deliberately separate from
+	// the poll interval. Each enabled stream waits out the full window, one
+	// after another, inside the tick, so when those windows add up to more
+	// than Interval the next tick (and the next round of HTTP sampling) is
+	// delayed. Defaults to defaultSubscriptionWindow.
+	SubscriptionWindowSeconds int `json:"subscriptionWindowSeconds,omitempty"`
+}
+
+// ProgramAccountsProbe describes one point in the getProgramAccounts
+// size/filter matrix: a program to query plus the filters that shape how
+// much data comes back.
+type ProgramAccountsProbe struct {
+	// Name identifies this probe in MethodStats, e.g. "small-filtered",
+	// "medium-filtered" or "unfiltered-dump".
+	Name string `json:"name"`
+	// Program is the account owner passed as the gPA target.
+	Program solana.PublicKey `json:"program"`
+	// DataSize filters accounts by exact byte length; 0 means no dataSize
+	// filter is applied.
+	DataSize int            `json:"dataSize,omitempty"`
+	Memcmp   []MemcmpFilter `json:"memcmp,omitempty"`
+}
+
+// MemcmpFilter mirrors Solana's memcmp filter: bytes, base58-encoded, must
+// match at offset.
+type MemcmpFilter struct {
+	Offset int    `json:"offset"`
+	Bytes  string `json:"bytes"`
+}
+
+// NotifierConfig describes a single alerting sink. Type selects which
+// concrete Notifier to build; the remaining fields are only relevant to
+// some types (e.g. BotAPIKey/ChatIDs only apply to "telegram").
+type NotifierConfig struct {
+	Type       string   `json:"type"`
+	WebhookURL string   `json:"webhookUrl,omitempty"`
+	BotAPIKey  string   `json:"botApiKey,omitempty"`
+	ChatIDs    []string `json:"chatIds,omitempty"`
 }
 
 type RPCConfig struct {
-	ID         string `json:"id"`
-	Endpoint   string `json:"endpoint"`
-	RateLimit  int    `json:"rateLimit"`
-	SampleSize int    `json:"sampleSize"`
+	ID         string       `json:"id"`
+	Endpoint   string       `json:"endpoint"`
+	WSEndpoint string       `json:"wsEndpoint"`
+	RateLimit  int          `json:"rateLimit"`
+	SampleSize int          `json:"sampleSize"`
+	Retry      *RetryConfig `json:"retry,omitempty"`
+	// BudgetMs is the SLO threshold, in milliseconds, used to compute
+	// MethodStats.Latency.PercentWithinBudgetMs. Leave unset to disable it.
+	BudgetMs int64 `json:"budgetMs,omitempty"`
+	// GRPC configures the optional Yellowstone/Geyser transport for this
+	// provider. Leave nil to skip it.
+	GRPC *GRPCConfig `json:"grpc,omitempty"`
+}
+
+// GRPCConfig dials a Yellowstone/Geyser gRPC endpoint, benchmarked
+// alongside the HTTP and WSS methods on RPCConfig for the same account set.
+type GRPCConfig struct {
+	Endpoint string `json:"endpoint"`
+	// Token, when set, is sent as the "x-token" metadata header some
+	// Geyser providers require for auth.
+	Token string `json:"token,omitempty"`
+}
+
+// RetryConfig controls the decorrelated-jitter exponential backoff applied
+// to transient failures in post. Delays are computed in milliseconds as
+// min(MaxDelayMs, BaseDelayMs * Multiplier^attempt), then randomized within
+// +/- Jitter of that value.
+type RetryConfig struct {
+	MaxAttempts int     `json:"maxAttempts"`
+	BaseDelayMs int64   `json:"baseDelayMs"`
+	MaxDelayMs  int64   `json:"maxDelayMs"`
+	Multiplier  float64 `json:"multiplier"`
+	Jitter      float64 `json:"jitter"`
 }
 
 type Report struct {
@@ -41,6 +122,40 @@ type MethodStats struct {
 	TotalDataRetrieved    float64
 	PositiveResponseCount int
 	NegativeResponseCount int
+	// Latency is computed over TotalResponseTime via a streaming quantile
+	// estimator, so tail behavior (p95/p99) survives even though Avg*
+	// above only ever shows the mean.
+	Latency *LatencyStats
+	// Subscription is only set for streaming pseudo-methods such as
+	// accountSubscribe/programSubscribe, where a single long-lived
+	// connection produces many notifications instead of one response
+	// per sample.
+	Subscription *SubscriptionStats
+}
+
+// SubscriptionStats in milliseconds, collected over the lifetime of a single
+// websocket (or equivalent streaming) subscription.
+type SubscriptionStats struct {
+	TimeToFirstNotification int64
+	// InterNotificationLatencies holds the gap, in milliseconds, between
+	// consecutive notifications. Its stddev is the jitter of the stream.
+	InterNotificationLatencies []int64
+	NotificationCount          int
+	NotificationsPerSlot       float64
+	DroppedCount               int
+	GapCount                   int
+	// ReconnectCount is only relevant to transports that redial on a
+	// dropped stream instead of giving up, such as the Geyser gRPC one.
+	ReconnectCount int
+	// TotalDataRetrieved, in bytes, is only populated by transports that
+	// carry the account payload in every update (e.g. Geyser), so
+	// throughput can be compared against the HTTP methods.
+	TotalDataRetrieved int64
+	// JitterStdDevMs is the standard deviation of InterNotificationLatencies.
+	// It lives here rather than in MethodStats.AvgTotalResponseTime because
+	// it isn't a response time at all, and callers reading that field
+	// shouldn't have to know it means something different for subscriptions.
+	JitterStdDevMs float64
 }
 
 // Stats in milliseconds and bytes for data size.
@@ -50,6 +165,17 @@ type Stats struct {
 	FirstResponseTime  int64
 	TotalResponseTime  int64
 	TotalDataRetrieved int64
+	// RetryCount and RetryWaitMs are only non-zero when the RPCConfig's
+	// RetryConfig caused post to be attempted more than once.
+	RetryCount  int
+	RetryWaitMs int64
+	// TimeTo1KB and TimeTo1MB, like FirstResponseTime, are measured from
+	// the moment the request was sent. They're only meaningful for
+	// responses large enough to cross that threshold, which in practice
+	// means getProgramAccounts: gPA is the method most likely to expose
+	// whether a provider streams its response or buffers the whole thing.
+	TimeTo1KB int64
+	TimeTo1MB int64
 }
 
 type RPCCall struct {
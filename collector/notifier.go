@@ -2,13 +2,128 @@ package collector
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
 )
 
+// Event carries the structured data behind an alert.
+type Event struct {
+	RPCID       string
+	Method      string
+	Err         string
+	SampleCount int
+}
+
+// Key identifies events that should be considered duplicates of one another
+// by ThrottledNotifier.
+func (e Event) Key() string {
+	return e.RPCID + "|" + e.Method + "|" + e.Err
+}
+
+// String renders the event as the plain-text message most channels send.
+func (e Event) String() string {
+	var b strings.Builder
+	b.WriteString(e.RPCID)
+	if e.Method != "" {
+		b.WriteString(" " + e.Method)
+	}
+	b.WriteString(": " + e.Err)
+	if e.SampleCount > 0 {
+		fmt.Fprintf(&b, " (sample_count=%d)", e.SampleCount)
+	}
+	return b.String()
+}
+
+// Notifier sends an alert about an Event to some external channel.
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// NoopNotifier discards every event; it's the default when none is configured.
+type NoopNotifier struct{}
+
+func (NoopNotifier) Notify(ctx context.Context, event Event) error { return nil }
+
+// CompositeNotifier fans an event out to every wrapped Notifier, continuing
+// on to the rest even if one sink fails.
+type CompositeNotifier struct {
+	notifiers []Notifier
+}
+
+func NewCompositeNotifier(notifiers ...Notifier) *CompositeNotifier {
+	return &CompositeNotifier{notifiers: notifiers}
+}
+
+func (c *CompositeNotifier) Notify(ctx context.Context, event Event) error {
+	var errs []error
+	for _, n := range c.notifiers {
+		if err := n.Notify(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ThrottledNotifier coalesces identical events seen within window: the first
+// is forwarded immediately, later ones are counted into a summary sent once
+// window elapses.
+type ThrottledNotifier struct {
+	inner  Notifier
+	window time.Duration
+
+	mu     sync.Mutex
+	counts map[string]*int32
+}
+
+func NewThrottledNotifier(inner Notifier, window time.Duration) *ThrottledNotifier {
+	return &ThrottledNotifier{
+		inner:  inner,
+		window: window,
+		counts: make(map[string]*int32),
+	}
+}
+
+func (t *ThrottledNotifier) Notify(ctx context.Context, event Event) error {
+	key := event.Key()
+
+	t.mu.Lock()
+	if count, seen := t.counts[key]; seen {
+		atomic.AddInt32(count, 1)
+		t.mu.Unlock()
+		return nil
+	}
+
+	count := new(int32)
+	*count = 1
+	t.counts[key] = count
+	t.mu.Unlock()
+
+	err := t.inner.Notify(ctx, event)
+
+	time.AfterFunc(t.window, func() {
+		t.mu.Lock()
+		delete(t.counts, key)
+		t.mu.Unlock()
+
+		if n := atomic.LoadInt32(count); n > 1 {
+			summary := event
+			summary.Err = fmt.Sprintf("%s (x%d in %s)", event.Err, n, t.window)
+			t.inner.Notify(ctx, summary)
+		}
+	})
+
+	return err
+}
+
 // Notifier send a notification throw a telegram bot
 // Using sendMessage method.
 // https://core.telegram.org/bots/api#sendmessage
@@ -19,42 +134,126 @@ type TGMessage struct {
 	Text   string `json:"text"`
 }
 
-type Notifier struct {
+type TelegramNotifier struct {
 	botAPIKey string
-	subs      []string
+	chatIDs   []string
 }
 
-func NewNotifier() *Notifier {
-	// notifiers separated by comma
-	subsStr := os.Getenv("NOTIFIER_SUBS")
-	subs := strings.Split(subsStr, ",")
-
-	return &Notifier{
-		botAPIKey: os.Getenv("TGBOT_API_KEY"),
-		subs:      subs,
-	}
+func NewTelegramNotifier(botAPIKey string, chatIDs []string) *TelegramNotifier {
+	return &TelegramNotifier{botAPIKey: botAPIKey, chatIDs: chatIDs}
 }
 
-func (svc *Notifier) Notify(text string) error {
-	for _, sub := range svc.subs {
-		msg := TGMessage{
-			ChatID: sub,
-			Text:   text,
-		}
+func (tg *TelegramNotifier) Notify(ctx context.Context, event Event) error {
+	text := event.String()
 
-		b, err := json.Marshal(&msg)
-		if err != nil {
+	for _, chatID := range tg.chatIDs {
+		msg := TGMessage{ChatID: chatID, Text: text}
+		if err := postJSON(ctx, fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", tg.botAPIKey), msg); err != nil {
 			return err
 		}
+	}
+
+	return nil
+}
 
-		body := bytes.NewBuffer(b)
+// SlackNotifier posts to a Slack incoming webhook.
+// https://api.slack.com/messaging/webhooks
+type SlackNotifier struct {
+	webhookURL string
+}
 
-		url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", svc.botAPIKey)
-		_, err = http.Post(url, "application/json", body)
-		if err != nil {
-			return err
+func NewSlackNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{webhookURL: webhookURL}
+}
+
+func (s *SlackNotifier) Notify(ctx context.Context, event Event) error {
+	return postJSON(ctx, s.webhookURL, struct {
+		Text string `json:"text"`
+	}{Text: event.String()})
+}
+
+// DiscordNotifier posts to a Discord webhook.
+// https://discord.com/developers/docs/resources/webhook#execute-webhook
+type DiscordNotifier struct {
+	webhookURL string
+}
+
+func NewDiscordNotifier(webhookURL string) *DiscordNotifier {
+	return &DiscordNotifier{webhookURL: webhookURL}
+}
+
+func (d *DiscordNotifier) Notify(ctx context.Context, event Event) error {
+	return postJSON(ctx, d.webhookURL, struct {
+		Content string `json:"content"`
+	}{Content: event.String()})
+}
+
+// WebhookNotifier posts the Event itself, as JSON, to an arbitrary URL.
+type WebhookNotifier struct {
+	webhookURL string
+}
+
+func NewWebhookNotifier(webhookURL string) *WebhookNotifier {
+	return &WebhookNotifier{webhookURL: webhookURL}
+}
+
+func (w *WebhookNotifier) Notify(ctx context.Context, event Event) error {
+	return postJSON(ctx, w.webhookURL, event)
+}
+
+// buildNotifier constructs the Notifier described by cfg.
+func buildNotifier(cfg *Config) Notifier {
+	notifiers := make([]Notifier, 0, len(cfg.Notifiers))
+	for _, nc := range cfg.Notifiers {
+		switch nc.Type {
+		case "telegram":
+			notifiers = append(notifiers, NewTelegramNotifier(nc.BotAPIKey, nc.ChatIDs))
+		case "slack":
+			notifiers = append(notifiers, NewSlackNotifier(nc.WebhookURL))
+		case "discord":
+			notifiers = append(notifiers, NewDiscordNotifier(nc.WebhookURL))
+		case "webhook":
+			notifiers = append(notifiers, NewWebhookNotifier(nc.WebhookURL))
+		case "noop", "":
+			notifiers = append(notifiers, NoopNotifier{})
+		default:
+			log.Debug().Str("type", nc.Type).Msg("buildNotifier: unknown notifier type, skipping")
 		}
 	}
 
+	if len(notifiers) == 0 {
+		return NoopNotifier{}
+	}
+
+	var notifier Notifier = NewCompositeNotifier(notifiers...)
+	if cfg.ThrottleWindowSeconds > 0 {
+		notifier = NewThrottledNotifier(notifier, time.Duration(cfg.ThrottleWindowSeconds)*time.Second)
+	}
+
+	return notifier
+}
+
+func postJSON(ctx context.Context, url string, payload any) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("postJSON: %s returned status %d", url, resp.StatusCode)
+	}
+
 	return nil
 }
@@ -0,0 +1,52 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+)
+
+// getProgramAccounts runs a single point of the size/filter matrix described
+// by probe. The method name in the resulting MethodStats is suffixed with
+// the probe's name (e.g. "getProgramAccounts:small-filtered") so providers
+// can be compared across the matrix rather than averaged into one number.
+func getProgramAccounts(
+	ctx context.Context,
+	rpc *RPCConfig,
+	probe ProgramAccountsProbe,
+	metrics *Metrics,
+) (*MethodStats, error) {
+	filters := make([]any, 0, len(probe.Memcmp)+1)
+	if probe.DataSize > 0 {
+		filters = append(filters, struct {
+			DataSize int `json:"dataSize"`
+		}{DataSize: probe.DataSize})
+	}
+	for _, m := range probe.Memcmp {
+		filters = append(filters, struct {
+			Memcmp struct {
+				Offset int    `json:"offset"`
+				Bytes  string `json:"bytes"`
+			} `json:"memcmp"`
+		}{Memcmp: struct {
+			Offset int    `json:"offset"`
+			Bytes  string `json:"bytes"`
+		}{Offset: m.Offset, Bytes: m.Bytes}})
+	}
+
+	opts := struct {
+		Encoding string `json:"encoding"`
+		Filters  []any  `json:"filters,omitempty"`
+	}{
+		Encoding: "base64",
+		Filters:  filters,
+	}
+
+	call := RPCCall{
+		JsonRPC: "2.0",
+		ID:      1,
+		Method:  "getProgramAccounts",
+		Params:  []any{probe.Program.String(), opts},
+	}
+
+	return collectStats(ctx, rpc, &call, fmt.Sprintf("getProgramAccounts:%s", probe.Name), metrics)
+}
@@ -0,0 +1,212 @@
+package collector
+
+import (
+	"math"
+	"sort"
+)
+
+// LatencyStats summarizes a stream of latency samples (in milliseconds)
+// without needing to keep every sample around: percentiles come from the
+// P² estimators in QuantileTracker, and StdDev from Welford's algorithm.
+type LatencyStats struct {
+	P50                   float64
+	P90                   float64
+	P95                   float64
+	P99                   float64
+	Max                   float64
+	StdDev                float64
+	PercentWithinBudgetMs float64
+}
+
+// QuantileTracker accumulates latency samples in a single pass, updating a
+// P² estimator per target quantile plus a running mean/variance, so memory
+// stays constant no matter how many samples are observed.
+type QuantileTracker struct {
+	budgetMs float64
+
+	p50, p90, p95, p99 *p2Estimator
+	max                float64
+
+	count        int
+	mean         float64
+	m2           float64 // Welford's running sum of squared deviations
+	withinBudget int
+}
+
+// NewQuantileTracker creates a tracker. budgetMs is the SLO threshold used
+// for PercentWithinBudgetMs; pass 0 if no budget is configured.
+func NewQuantileTracker(budgetMs float64) *QuantileTracker {
+	return &QuantileTracker{
+		budgetMs: budgetMs,
+		p50:      newP2Estimator(0.50),
+		p90:      newP2Estimator(0.90),
+		p95:      newP2Estimator(0.95),
+		p99:      newP2Estimator(0.99),
+	}
+}
+
+// Observe records a single latency sample, in milliseconds.
+func (t *QuantileTracker) Observe(ms float64) {
+	t.p50.add(ms)
+	t.p90.add(ms)
+	t.p95.add(ms)
+	t.p99.add(ms)
+
+	if ms > t.max {
+		t.max = ms
+	}
+
+	t.count++
+	delta := ms - t.mean
+	t.mean += delta / float64(t.count)
+	t.m2 += delta * (ms - t.mean)
+
+	if t.budgetMs > 0 && ms <= t.budgetMs {
+		t.withinBudget++
+	}
+}
+
+// Snapshot returns the current estimate. It can be called repeatedly as
+// more samples are observed.
+func (t *QuantileTracker) Snapshot() *LatencyStats {
+	stats := &LatencyStats{
+		P50:    t.p50.value(),
+		P90:    t.p90.value(),
+		P95:    t.p95.value(),
+		P99:    t.p99.value(),
+		Max:    t.max,
+		StdDev: t.stdDev(),
+	}
+
+	if t.budgetMs > 0 && t.count > 0 {
+		stats.PercentWithinBudgetMs = 100 * float64(t.withinBudget) / float64(t.count)
+	}
+
+	return stats
+}
+
+func (t *QuantileTracker) stdDev() float64 {
+	if t.count < 2 {
+		return 0
+	}
+
+	return math.Sqrt(t.m2 / float64(t.count))
+}
+
+// p2Estimator implements the P² algorithm (Jain & Chlamtac, 1985) for
+// estimating a single quantile from a data stream in O(1) memory, without
+// storing or sorting samples.
+type p2Estimator struct {
+	quantile float64
+	n        [5]int     // marker positions
+	np       [5]float64 // desired marker positions
+	dn       [5]float64 // desired marker position increments
+	q        [5]float64 // marker heights
+	count    int
+}
+
+func newP2Estimator(quantile float64) *p2Estimator {
+	return &p2Estimator{
+		quantile: quantile,
+		dn:       [5]float64{0, quantile / 2, quantile, (1 + quantile) / 2, 1},
+	}
+}
+
+func (e *p2Estimator) add(x float64) {
+	e.count++
+
+	switch {
+	case e.count <= 5:
+		e.q[e.count-1] = x
+		if e.count == 5 {
+			sortFloat5(&e.q)
+			for i := 0; i < 5; i++ {
+				e.n[i] = i + 1
+				e.np[i] = 1 + 4*e.dn[i]
+			}
+		}
+		return
+	}
+
+	k := 0
+	switch {
+	case x < e.q[0]:
+		e.q[0] = x
+		k = 0
+	case x >= e.q[4]:
+		e.q[4] = x
+		k = 3
+	default:
+		for i := 0; i < 4; i++ {
+			if e.q[i] <= x && x < e.q[i+1] {
+				k = i
+				break
+			}
+		}
+	}
+
+	for i := k + 1; i < 5; i++ {
+		e.n[i]++
+	}
+	for i := 0; i < 5; i++ {
+		e.np[i] += e.dn[i]
+	}
+
+	for i := 1; i < 4; i++ {
+		d := e.np[i] - float64(e.n[i])
+		if (d >= 1 && e.n[i+1]-e.n[i] > 1) || (d <= -1 && e.n[i-1]-e.n[i] < -1) {
+			sign := 1
+			if d < 0 {
+				sign = -1
+			}
+
+			qNew := e.parabolic(i, sign)
+			if e.q[i-1] < qNew && qNew < e.q[i+1] {
+				e.q[i] = qNew
+			} else {
+				e.q[i] = e.linear(i, sign)
+			}
+
+			e.n[i] += sign
+		}
+	}
+}
+
+func (e *p2Estimator) parabolic(i, sign int) float64 {
+	d := float64(sign)
+	return e.q[i] + d/float64(e.n[i+1]-e.n[i-1])*
+		((float64(e.n[i]-e.n[i-1])+d)*(e.q[i+1]-e.q[i])/float64(e.n[i+1]-e.n[i])+
+			(float64(e.n[i+1]-e.n[i])-d)*(e.q[i]-e.q[i-1])/float64(e.n[i]-e.n[i-1]))
+}
+
+func (e *p2Estimator) linear(i, sign int) float64 {
+	d := float64(sign)
+	return e.q[i] + d*(e.q[i+sign]-e.q[i])/float64(e.n[i+sign]-e.n[i])
+}
+
+func (e *p2Estimator) value() float64 {
+	if e.count == 0 {
+		return 0
+	}
+
+	// Before the 5 markers are initialized, e.q only has e.count
+	// meaningful entries; the rest are unwritten zeros, so only the
+	// populated prefix may be sorted and indexed.
+	if e.count < 5 {
+		sorted := make([]float64, e.count)
+		copy(sorted, e.q[:e.count])
+		sort.Float64s(sorted)
+		idx := int(e.quantile * float64(e.count-1))
+		return sorted[idx]
+	}
+
+	return e.q[2]
+}
+
+func sortFloat5(a *[5]float64) {
+	for i := 1; i < 5; i++ {
+		for j := i; j > 0 && a[j-1] > a[j]; j-- {
+			a[j-1], a[j] = a[j], a[j-1]
+		}
+	}
+}
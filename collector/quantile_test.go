@@ -0,0 +1,55 @@
+package collector
+
+import (
+	"math"
+	"testing"
+)
+
+func TestQuantileTrackerKnownDistribution(t *testing.T) {
+	tracker := NewQuantileTracker(0)
+	for i := 0; i < 100; i++ {
+		tracker.Observe(float64(i))
+	}
+
+	snapshot := tracker.Snapshot()
+
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"P50", snapshot.P50, 50},
+		{"P90", snapshot.P90, 90},
+		{"P95", snapshot.P95, 95},
+		{"P99", snapshot.P99, 99},
+	}
+
+	const tolerance = 5 // P2 is an approximation, not exact order statistics
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > tolerance {
+			t.Errorf("%s = %f, want within %v of %f", tt.name, tt.got, tolerance, tt.want)
+		}
+	}
+
+	if snapshot.Max != 99 {
+		t.Errorf("Max = %f, want 99", snapshot.Max)
+	}
+}
+
+func TestQuantileTrackerBeforeFiveSamples(t *testing.T) {
+	tracker := NewQuantileTracker(0)
+	tracker.Observe(123)
+
+	if got := tracker.Snapshot().P50; got != 123 {
+		t.Errorf("P50 after a single sample = %f, want 123", got)
+	}
+
+	tracker = NewQuantileTracker(0)
+	for _, v := range []float64{100, 200, 300} {
+		tracker.Observe(v)
+	}
+
+	if got := tracker.Snapshot().P50; got != 200 {
+		t.Errorf("P50 after three samples = %f, want 200", got)
+	}
+}
@@ -0,0 +1,77 @@
+package collector
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// defaultRetryConfig is used whenever an RPCConfig doesn't set Retry. A
+// MaxAttempts of 1 means post is tried exactly once, matching the old
+// behavior of callers that predate retries.
+var defaultRetryConfig = RetryConfig{MaxAttempts: 1}
+
+// postWithRetry wraps post with decorrelated-jitter exponential backoff.
+// Only network errors and HTTP 429/5xx responses are retried; a JSON-RPC
+// application error in the response body (stats.Err with a 200 status) is
+// returned as-is, since retrying it would just get the same error back.
+func postWithRetry(ctx context.Context, rpc *RPCConfig, call *RPCCall) (*Stats, error) {
+	cfg := rpc.Retry
+	if cfg == nil {
+		cfg = &defaultRetryConfig
+	}
+
+	var (
+		stats       *Stats
+		err         error
+		retries     int
+		totalWaitMs int64
+	)
+
+	for attempt := 0; ; attempt++ {
+		stats, err = post(ctx, rpc, call)
+
+		retryable := err != nil || (stats != nil && isRetryableStatus(stats.StatusCode))
+		if !retryable || attempt+1 >= cfg.MaxAttempts {
+			break
+		}
+
+		delay := backoffDelay(cfg, attempt)
+		totalWaitMs += delay.Milliseconds()
+		retries++
+
+		select {
+		case <-ctx.Done():
+			return stats, err
+		case <-time.After(delay):
+		}
+	}
+
+	if stats != nil {
+		stats.RetryCount = retries
+		stats.RetryWaitMs = totalWaitMs
+	}
+
+	return stats, err
+}
+
+// isRetryableStatus reports whether status is a transient failure worth
+// retrying: rate limiting or a server-side error.
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// backoffDelay computes the decorrelated-jitter exponential backoff delay
+// for a given zero-based attempt number.
+func backoffDelay(cfg *RetryConfig, attempt int) time.Duration {
+	base := float64(cfg.BaseDelayMs) * math.Pow(cfg.Multiplier, float64(attempt))
+	capped := math.Min(base, float64(cfg.MaxDelayMs))
+
+	jitterRange := capped * cfg.Jitter
+	lo := capped - jitterRange
+	delay := lo + rand.Float64()*2*jitterRange
+
+	return time.Duration(delay) * time.Millisecond
+}
@@ -0,0 +1,258 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// collectAccountSubscribeStats opens an accountSubscribe stream for account
+// and keeps it open for window, recording one SubscriptionStats sample.
+func collectAccountSubscribeStats(
+	ctx context.Context,
+	rpc *RPCConfig,
+	account solana.PublicKey,
+	window time.Duration,
+	metrics *Metrics,
+) (*MethodStats, error) {
+	const name = "accountSubscribe"
+	result := &MethodStats{Name: name}
+
+	sub, closeConn, err := subscribeAccount(ctx, rpc.WSEndpoint, account)
+	if err != nil {
+		result.ErrMsgs = append(result.ErrMsgs, err.Error())
+		result.NegativeResponseCount++
+		return result, nil
+	}
+	defer closeConn()
+
+	stats, err := readSubscription(ctx, window, func(recvCtx context.Context) (uint64, error) {
+		res, err := sub.Recv(recvCtx)
+		if err != nil {
+			return 0, err
+		}
+		return res.Context.Slot, nil
+	})
+	if err != nil {
+		result.ErrMsgs = append(result.ErrMsgs, err.Error())
+		result.NegativeResponseCount++
+		return result, nil
+	}
+
+	result.PositiveResponseCount++
+	result.Subscription = stats
+	stats.JitterStdDevMs = jitterStddev(stats)
+
+	if metrics != nil {
+		metrics.Observe(rpc.ID, name, &Stats{
+			StatusCode:        200,
+			FirstResponseTime: stats.TimeToFirstNotification,
+		})
+	}
+
+	return result, nil
+}
+
+// collectProgramSubscribeStats mirrors collectAccountSubscribeStats for
+// programSubscribe, tracking every account update emitted for program.
+func collectProgramSubscribeStats(
+	ctx context.Context,
+	rpc *RPCConfig,
+	program solana.PublicKey,
+	window time.Duration,
+	metrics *Metrics,
+) (*MethodStats, error) {
+	const name = "programSubscribe"
+	result := &MethodStats{Name: name}
+
+	sub, closeConn, err := subscribeProgram(ctx, rpc.WSEndpoint, program)
+	if err != nil {
+		result.ErrMsgs = append(result.ErrMsgs, err.Error())
+		result.NegativeResponseCount++
+		return result, nil
+	}
+	defer closeConn()
+
+	stats, err := readSubscription(ctx, window, func(recvCtx context.Context) (uint64, error) {
+		res, err := sub.Recv(recvCtx)
+		if err != nil {
+			return 0, err
+		}
+		return res.Context.Slot, nil
+	})
+	if err != nil {
+		result.ErrMsgs = append(result.ErrMsgs, err.Error())
+		result.NegativeResponseCount++
+		return result, nil
+	}
+
+	result.PositiveResponseCount++
+	result.Subscription = stats
+	stats.JitterStdDevMs = jitterStddev(stats)
+
+	if metrics != nil {
+		metrics.Observe(rpc.ID, name, &Stats{
+			StatusCode:        200,
+			FirstResponseTime: stats.TimeToFirstNotification,
+		})
+	}
+
+	return result, nil
+}
+
+func subscribeAccount(ctx context.Context, endpoint string, account solana.PublicKey) (*ws.AccountSubscription, func(), error) {
+	if endpoint == "" {
+		return nil, nil, fmt.Errorf("accountSubscribe: no wsEndpoint configured")
+	}
+
+	client, err := ws.Connect(ctx, endpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sub, err := client.AccountSubscribe(account, rpc.CommitmentConfirmed)
+	if err != nil {
+		client.Close()
+		return nil, nil, err
+	}
+
+	return sub, func() {
+		sub.Unsubscribe()
+		client.Close()
+	}, nil
+}
+
+func subscribeProgram(ctx context.Context, endpoint string, program solana.PublicKey) (*ws.ProgramSubscription, func(), error) {
+	if endpoint == "" {
+		return nil, nil, fmt.Errorf("programSubscribe: no wsEndpoint configured")
+	}
+
+	client, err := ws.Connect(ctx, endpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sub, err := client.ProgramSubscribe(program, rpc.CommitmentConfirmed)
+	if err != nil {
+		client.Close()
+		return nil, nil, err
+	}
+
+	return sub, func() {
+		sub.Unsubscribe()
+		client.Close()
+	}, nil
+}
+
+// readSubscription drains notifications for window, recording the time of
+// the first one and the gap between every pair that follows. recv is the
+// subscription's blocking Recv method, returning the slot of each
+// notification so GapCount can be tracked; it runs in its own goroutine so a
+// stalled stream can still be bounded by window.
+func readSubscription(
+	ctx context.Context,
+	window time.Duration,
+	recv func(context.Context) (uint64, error),
+) (*SubscriptionStats, error) {
+	recvCtx, cancel := context.WithTimeout(ctx, window)
+	defer cancel()
+
+	type notification struct {
+		at   time.Time
+		slot uint64
+		err  error
+	}
+	notifications := make(chan notification, 1)
+
+	go func() {
+		for recvCtx.Err() == nil {
+			slot, err := recv(recvCtx)
+			notifications <- notification{at: time.Now(), slot: slot, err: err}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	stats := &SubscriptionStats{}
+	start := time.Now()
+	var (
+		last     time.Time
+		lastSlot uint64
+	)
+
+loop:
+	for {
+		select {
+		case <-recvCtx.Done():
+			break loop
+		case n := <-notifications:
+			if n.err != nil {
+				stats.DroppedCount++
+				break loop
+			}
+
+			if stats.NotificationCount == 0 {
+				stats.TimeToFirstNotification = n.at.Sub(start).Milliseconds()
+			} else {
+				stats.InterNotificationLatencies = append(stats.InterNotificationLatencies, n.at.Sub(last).Milliseconds())
+				if n.slot > lastSlot+1 {
+					stats.GapCount += int(n.slot - lastSlot - 1)
+				}
+			}
+
+			stats.NotificationCount++
+			last = n.at
+			lastSlot = n.slot
+		}
+	}
+
+	if stats.NotificationCount == 0 {
+		return nil, fmt.Errorf("no notifications received within %s", window)
+	}
+
+	stats.NotificationsPerSlot = notificationsPerSlot(stats.NotificationCount, time.Since(start))
+
+	return stats, nil
+}
+
+// notificationsPerSlot divides count by how many ~400ms Solana slots elapsed
+// in elapsed, which should be actual collection time rather than the
+// configured window (a dropped or reconnecting stream can fall short of it).
+func notificationsPerSlot(count int, elapsed time.Duration) float64 {
+	elapsedSlots := elapsed.Seconds() / 0.4
+	if elapsedSlots <= 0 {
+		return 0
+	}
+
+	return float64(count) / elapsedSlots
+}
+
+// jitterStddev returns the standard deviation, in milliseconds, of the
+// inter-notification latencies recorded for a subscription.
+func jitterStddev(s *SubscriptionStats) float64 {
+	n := len(s.InterNotificationLatencies)
+	if n == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, v := range s.InterNotificationLatencies {
+		sum += float64(v)
+	}
+	mean := sum / float64(n)
+
+	var variance float64
+	for _, v := range s.InterNotificationLatencies {
+		d := float64(v) - mean
+		variance += d * d
+	}
+	variance /= float64(n)
+
+	return math.Sqrt(variance)
+}
@@ -12,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Gealber/rpc-notifier/collector"
 	"github.com/gagliardetto/solana-go"
 	"golang.org/x/sync/errgroup"
 )
@@ -40,6 +41,11 @@ type MethodStats struct {
 	TotalDataRetrieved    float64
 	PositiveResponseCount int
 	NegativeResponseCount int
+	// Elapsed is real wall-clock time spent collecting StatsSamples. Samples
+	// are dispatched concurrently through an errgroup behind a rate-limit
+	// bucket, so it can't be derived from AvgTotalResponseTime * sample
+	// count; it's measured directly around that dispatch instead.
+	Elapsed time.Duration
 }
 
 // Stats in milliseconds and bytes for data size.
@@ -94,24 +100,44 @@ func main() {
 	}
 	defer records.Close()
 
-	records.WriteString("rpc|method|status_code|frt|trt|total_data_retrieved(KB)\n")
+	records.WriteString("rpc|method|status_code|frt|trt|total_data_retrieved(KB)|p50_trt|p90_trt|p95_trt|p99_trt|max_trt\n")
 
 	// save reports to later generate graphs
 	for _, r := range reports {
 		fmt.Printf("RPC_NAME: %s\n", r.RPCID)
 		for _, m := range r.MethodsStats {
+			latency := totalResponseTimeLatency(m.StatsSamples)
+
 			for _, s := range m.StatsSamples {
-				line := fmt.Sprintf("%s|%s|%d|%d|%d|%f\n", r.RPCID, m.Name, s.StatusCode, s.FirstResponseTime, s.TotalResponseTime, float64(s.TotalDataRetrieved)/1024)
+				line := fmt.Sprintf("%s|%s|%d|%d|%d|%f|%f|%f|%f|%f|%f\n",
+					r.RPCID, m.Name, s.StatusCode, s.FirstResponseTime, s.TotalResponseTime, float64(s.TotalDataRetrieved)/1024,
+					latency.P50, latency.P90, latency.P95, latency.P99, latency.Max)
 				records.WriteString(line)
 			}
 
-			fmt.Printf("Method: %s\nAvg FRT: %f\nAvg TRT: %f\nPositive Response Count: %d\nNegative Response Count: %d\nTotal Data Retrieved(MB): %f\n", m.Name, m.AvgFirstResponseTime, m.AvgTotalResponseTime, m.PositiveResponseCount, m.NegativeResponseCount, m.TotalDataRetrieved/1048576)
+			throughput := collector.FormatThroughput(int64(m.TotalDataRetrieved), m.Elapsed)
+			rate := collector.FormatRate(len(m.StatsSamples), m.Elapsed)
+			fmt.Printf("Method: %s\nAvg FRT: %f\nAvg TRT: %f\nP50/P90/P95/P99/Max TRT(ms): %f/%f/%f/%f/%f\nPositive Response Count: %d\nNegative Response Count: %d\nTotal Data Retrieved(MB): %f (%s)\nRate: %s\n",
+				m.Name, m.AvgFirstResponseTime, m.AvgTotalResponseTime, latency.P50, latency.P90, latency.P95, latency.P99, latency.Max,
+				m.PositiveResponseCount, m.NegativeResponseCount, m.TotalDataRetrieved/1048576, throughput, rate)
 			fmt.Println("---------------------------------------------------------------------------------------")
 		}
 		fmt.Println("---------------------------------------------------------------------------------------")
 	}
 }
 
+// totalResponseTimeLatency feeds every sample's TotalResponseTime through
+// collector.QuantileTracker, the same streaming P² estimator the collector
+// package uses, instead of sorting the whole in-memory slice here.
+func totalResponseTimeLatency(samples []*Stats) *collector.LatencyStats {
+	tracker := collector.NewQuantileTracker(0)
+	for _, s := range samples {
+		tracker.Observe(float64(s.TotalResponseTime))
+	}
+
+	return tracker.Snapshot()
+}
+
 // collectResults collects the results for a given rpc configuration for each method.
 // n specifies the amount of calls to perform for each method.
 // The methods to be tested are getAccountInfo, getMultipleAccounts, and getProgramAccounts.
@@ -191,6 +217,7 @@ func collectStats(
 		mu sync.Mutex
 	)
 	counter := make(map[int64]int64)
+	start := time.Now()
 	for i := 0; i < amount; i++ {
 		g.Go(func() error {
 			<-bucket
@@ -224,6 +251,7 @@ func collectStats(
 		return nil, err
 	}
 	limiter.Stop()
+	result.Elapsed = time.Since(start)
 
 	result.AvgFirstResponseTime /= float64(amount)
 	result.AvgTotalResponseTime /= float64(amount)